@@ -0,0 +1,184 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+import "C"
+
+import (
+	"time"
+	"unsafe"
+)
+
+// Signature identifies the author or committer of a Commit: a name, an
+// email address and a timestamp.
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+func newSignatureFromC(sig *C.git_signature) *Signature {
+	when := time.Unix(int64(sig.when.time), 0).In(
+		time.FixedZone("", int(sig.when.offset)*60),
+	)
+	return &Signature{
+		Name:  C.GoString(sig.name),
+		Email: C.GoString(sig.email),
+		When:  when,
+	}
+}
+
+func (s *Signature) toC() (*C.git_signature, error) {
+	cname := C.CString(s.Name)
+	defer C.free(unsafe.Pointer(cname))
+	cemail := C.CString(s.Email)
+	defer C.free(unsafe.Pointer(cemail))
+	_, offset := s.When.Zone()
+	var csig *C.git_signature
+	if err := callC(func() C.int {
+		return C.git_signature_new(&csig, cname, cemail, C.git_time_t(s.When.Unix()), C.int(offset/60))
+	}); err != nil {
+		return nil, err
+	}
+	return csig, nil
+}
+
+// Commit represents a git commit.
+type Commit struct {
+	commit *C.struct_git_commit
+}
+
+// Free is used to deallocate a commit object.
+func (c *Commit) Free() {
+	C.git_commit_free(c.commit)
+}
+
+// Id returns the hash of the commit.
+func (c *Commit) Id() string {
+	return newOidFromC(C.git_commit_id(c.commit)).String()
+}
+
+// Tree returns the tree pointed by the commit.
+func (c *Commit) Tree() (*Tree, error) {
+	t := new(Tree)
+	if err := callC(func() C.int { return C.git_commit_tree(&t.tree, c.commit) }); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Author returns the signature of the commit's author.
+func (c *Commit) Author() *Signature {
+	return newSignatureFromC(C.git_commit_author(c.commit))
+}
+
+// Committer returns the signature of whoever committed the commit.
+func (c *Commit) Committer() *Signature {
+	return newSignatureFromC(C.git_commit_committer(c.commit))
+}
+
+// Message returns the full commit message.
+func (c *Commit) Message() string {
+	return C.GoString(C.git_commit_message(c.commit))
+}
+
+// Summary returns the short, one-line summary of the commit message.
+func (c *Commit) Summary() string {
+	return C.GoString(C.git_commit_summary(c.commit))
+}
+
+// Time returns the commit time, as recorded in the committer signature.
+func (c *Commit) Time() time.Time {
+	return c.Committer().When
+}
+
+// ParentCount returns the number of parents of the commit.
+func (c *Commit) ParentCount() uint {
+	return uint(C.git_commit_parentcount(c.commit))
+}
+
+// Parent returns the nth parent of the commit, via git_commit_parent.
+func (c *Commit) Parent(n uint) *Commit {
+	parent := new(Commit)
+	if err := callC(func() C.int { return C.git_commit_parent(&parent.commit, c.commit, C.uint(n)) }); err != nil {
+		return nil
+	}
+	return parent
+}
+
+// ParentId returns the id of the nth parent of the commit, without having to
+// look it up, via git_commit_parent_id.
+func (c *Commit) ParentId(n uint) *Oid {
+	coid := C.git_commit_parent_id(c.commit, C.uint(n))
+	if coid == nil {
+		return nil
+	}
+	return newOidFromC(coid)
+}
+
+// DefaultSignature builds a Signature from the repository's user.name and
+// user.email config values, falling back to git_signature_default (which
+// consults the environment and system defaults) when either is unset.
+func (r *Repository) DefaultSignature() (*Signature, error) {
+	config, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+	defer config.Free()
+	name, nameErr := config.GetString("user.name")
+	email, emailErr := config.GetString("user.email")
+	if nameErr == nil && emailErr == nil {
+		return &Signature{Name: name, Email: email, When: time.Now()}, nil
+	}
+	var csig *C.git_signature
+	if err := callC(func() C.int { return C.git_signature_default(&csig, r.repository) }); err != nil {
+		return nil, err
+	}
+	defer C.git_signature_free(csig)
+	return newSignatureFromC(csig), nil
+}
+
+// CreateCommit creates a new commit in the repository, via
+// git_commit_create, and updates refname (pass "" to skip updating any
+// reference) to point at it.
+func (r *Repository) CreateCommit(refname string, author, committer *Signature, message string, tree *Tree, parents ...*Commit) (*Oid, error) {
+	var crefname *C.char
+	if refname != "" {
+		crefname = C.CString(refname)
+		defer C.free(unsafe.Pointer(crefname))
+	}
+	cauthor, err := author.toC()
+	if err != nil {
+		return nil, err
+	}
+	defer C.git_signature_free(cauthor)
+	ccommitter, err := committer.toC()
+	if err != nil {
+		return nil, err
+	}
+	defer C.git_signature_free(ccommitter)
+	cmessage := C.CString(message)
+	defer C.free(unsafe.Pointer(cmessage))
+	cparents := make([]*C.git_commit, len(parents))
+	for i, parent := range parents {
+		cparents[i] = parent.commit
+	}
+	var cparentsPtr **C.git_commit
+	if len(cparents) > 0 {
+		cparentsPtr = &cparents[0]
+	}
+	var coid C.git_oid
+	if err := callC(func() C.int {
+		return C.git_commit_create(
+			&coid, r.repository, crefname, cauthor, ccommitter, nil, cmessage,
+			tree.tree, C.int(len(parents)), cparentsPtr,
+		)
+	}); err != nil {
+		return nil, err
+	}
+	return newOidFromC(&coid), nil
+}
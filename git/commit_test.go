@@ -0,0 +1,77 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommitAuthorAndMessage(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	commit, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer commit.Free()
+	if commit.Message() != "initial commit\n" {
+		t.Errorf("Want %q, got %q.", "initial commit\n", commit.Message())
+	}
+	if commit.Summary() != "initial commit" {
+		t.Errorf("Want %q, got %q.", "initial commit", commit.Summary())
+	}
+	if commit.ParentCount() != 0 {
+		t.Errorf("Want 0 parents, got %d.", commit.ParentCount())
+	}
+	author := commit.Author()
+	if author.Name == "" || author.Email == "" {
+		t.Errorf("Expected a non-empty author, got %#v.", author)
+	}
+}
+
+func TestCreateCommit(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	parent, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer parent.Free()
+	tree, err := parent.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	sig := &Signature{Name: "gogit", Email: "gogit@example.com", When: time.Now()}
+	oid, err := r.CreateCommit("HEAD", sig, sig, "second commit\n", tree, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer head.Free()
+	if head.Id() != oid.String() {
+		t.Errorf("Want HEAD to be %s, got %s.", oid, head.Id())
+	}
+	if head.ParentCount() != 1 {
+		t.Fatalf("Want 1 parent, got %d.", head.ParentCount())
+	}
+	if head.ParentId(0).String() != parent.Id() {
+		t.Errorf("Want parent %s, got %s.", parent.Id(), head.ParentId(0))
+	}
+}
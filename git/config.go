@@ -0,0 +1,347 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+//
+// extern int configForeachCallback(const char *name, const char *value, void *payload);
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// configCallbackMu serializes the package-wide configForeachFunc slot used to
+// bridge git_config_foreach_cb invocations back into Go. git_config_foreach
+// and git_config_get_multivar are synchronous, so holding the lock for the
+// duration of the underlying C call is enough to keep concurrent callers
+// from stepping on each other.
+var (
+	configCallbackMu  sync.Mutex
+	configForeachFunc func(name, value string) error
+	configForeachErr  error
+)
+
+//export configForeachCallback
+func configForeachCallback(name, value *C.char, payload unsafe.Pointer) C.int {
+	if err := configForeachFunc(C.GoString(name), C.GoString(value)); err != nil {
+		configForeachErr = err
+		return -1
+	}
+	return C.GIT_OK
+}
+
+// ConfigLevel represents the priority level of a configuration file, from
+// the system-wide file down to the repository-local one.
+//
+// Higher values take precedence over lower ones: a value set at
+// ConfigLevelLocal overrides the same key set at ConfigLevelGlobal, which in
+// turn overrides ConfigLevelXDG and ConfigLevelSystem.
+type ConfigLevel int
+
+const (
+	// ConfigLevelSystem is the system-wide configuration file;/etc/gitconfig
+	// on most systems.
+	ConfigLevelSystem ConfigLevel = C.GIT_CONFIG_LEVEL_SYSTEM
+
+	// ConfigLevelXDG is the XDG compatible configuration file;
+	// $XDG_CONFIG_HOME/git/config.
+	ConfigLevelXDG ConfigLevel = C.GIT_CONFIG_LEVEL_XDG
+
+	// ConfigLevelGlobal is the user-specific configuration file; also known
+	// as ~/.gitconfig.
+	ConfigLevelGlobal ConfigLevel = C.GIT_CONFIG_LEVEL_GLOBAL
+
+	// ConfigLevelLocal is the repository specific configuration file;
+	// $GIT_DIR/config on most repositories.
+	ConfigLevelLocal ConfigLevel = C.GIT_CONFIG_LEVEL_LOCAL
+
+	// ConfigLevelApp is the application specific configuration file;
+	// freely defined by a specific application.
+	ConfigLevelApp ConfigLevel = C.GIT_CONFIG_LEVEL_APP
+)
+
+// Config represents the configuration of a git repository.
+//
+// You can use it to retrieve or to define settings on the repository.
+//
+// A Config returned by Repository.Config is a merged, multi-level view: it
+// stacks the system, XDG, global, local and app files together and reads and
+// writes through the highest priority level available. Use OpenLevel to get
+// a Config instance bound to a single level.
+type Config struct {
+	config *C.struct_git_config
+}
+
+// Free is used to deallocate the Config instance. It should be called to
+// finish the instance. You can use it with the defer statement:
+//
+//     // get repository instance
+//     config, err := repo.Config()
+//     // check error
+//     defer config.Free()
+func (c *Config) Free() {
+	C.git_config_free(c.config)
+}
+
+// OpenLevel returns a new Config instance containing only the configuration
+// values from a single level of this Config, loaded via
+// git_config_open_level.
+//
+// This is how you target a specific file (e.g. ~/.gitconfig via
+// ConfigLevelGlobal) instead of the merged view: reads and writes on the
+// returned Config only ever touch that level. The caller is responsible for
+// calling Free on the returned Config.
+func (c *Config) OpenLevel(level ConfigLevel) (*Config, error) {
+	levelConfig := new(Config)
+	if err := callC(func() C.int {
+		return C.git_config_open_level(&levelConfig.config, c.config, C.git_config_level_t(level))
+	}); err != nil {
+		return nil, err
+	}
+	return levelConfig, nil
+}
+
+// AddFile adds a file on disk to this Config at the given level, via
+// git_config_add_file_ondisk.
+//
+// If force is true and a file already exists at the given level, it is
+// replaced; otherwise attempting to add a second file for a level that is
+// already populated returns an error.
+func (c *Config) AddFile(path string, level ConfigLevel, force bool) error {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var cforce C.int
+	if force {
+		cforce = 1
+	}
+	if err := callC(func() C.int {
+		return C.git_config_add_file_ondisk(c.config, cpath, C.git_config_level_t(level), cforce)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetBool is used to get boolean config values.
+//
+// The dot notation is used for configuration parameters. Example:
+//
+//     v, err := config.GetBool("core.ignorecase")
+//     // check errors and use v
+func (c *Config) GetBool(name string) (bool, error) {
+	var v C.int
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_config_get_bool(&v, c.config, cname) }); err != nil {
+		return false, err
+	}
+	return v == 1, nil
+}
+
+// SetBool is used to add a boolean setting to the configuration file.
+//
+// The format of the configuration parameter is the same as in GetBool. If the
+// configuration parameter is not declared in the config file, it will be
+// created. Example of use:
+//
+//     err := config.SetBool("core.ignorecase", true)
+//     if err != nil {
+//         panic(err)
+//     }
+func (c *Config) SetBool(name string, value bool) error {
+	var v C.int = 0
+	if value {
+		v = 1
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_config_set_bool(c.config, cname, v) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetBoolAt is like SetBool, but writes the value at the given level instead
+// of wherever the merged Config would normally write it.
+func (c *Config) SetBoolAt(name string, value bool, level ConfigLevel) error {
+	levelConfig, err := c.OpenLevel(level)
+	if err != nil {
+		return err
+	}
+	defer levelConfig.Free()
+	return levelConfig.SetBool(name, value)
+}
+
+// GetString is used to get string config values.
+//
+// The format of the configuration parameter is the same as in GetBool.
+func (c *Config) GetString(name string) (string, error) {
+	var v *C.char
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_config_get_string(&v, c.config, cname) }); err != nil {
+		return "", err
+	}
+	return C.GoString(v), nil
+}
+
+// SetString is used to add a string setting to the config file.
+//
+// The format of the configuration parameter is the same as in GetBool. If the
+// parameter is not declared in the config file, it will be created.
+func (c *Config) SetString(name, value string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(cvalue))
+	if err := callC(func() C.int { return C.git_config_set_string(c.config, cname, cvalue) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetStringAt is like SetString, but writes the value at the given level
+// instead of wherever the merged Config would normally write it.
+//
+// This is what callers need when they want to write to ~/.gitconfig
+// (ConfigLevelGlobal) rather than the repository's .git/config
+// (ConfigLevelLocal).
+func (c *Config) SetStringAt(name, value string, level ConfigLevel) error {
+	levelConfig, err := c.OpenLevel(level)
+	if err != nil {
+		return err
+	}
+	defer levelConfig.Free()
+	return levelConfig.SetString(name, value)
+}
+
+// GetInt64 is used to get int64 config values.
+//
+// The format of the configuration parameter is the same as in GetBool.
+func (c *Config) GetInt64(name string) (int64, error) {
+	var v C.int64_t
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_config_get_int64(&v, c.config, cname) }); err != nil {
+		return 0, err
+	}
+	return int64(v), nil
+}
+
+// SetInt64 is used to add a int64 setting to the config file.
+//
+// The format of the configuration parameter is the same as in GetBool. If the
+// parameter is not declared in the config file, it will be created.
+func (c *Config) SetInt64(name string, value int64) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_config_set_int64(c.config, cname, C.int64_t(value)) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetInt64At is like SetInt64, but writes the value at the given level
+// instead of wherever the merged Config would normally write it.
+func (c *Config) SetInt64At(name string, value int64, level ConfigLevel) error {
+	levelConfig, err := c.OpenLevel(level)
+	if err != nil {
+		return err
+	}
+	defer levelConfig.Free()
+	return levelConfig.SetInt64(name, value)
+}
+
+// Delete removes the given configuration entry, via git_config_delete_entry.
+func (c *Config) Delete(name string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_config_delete_entry(c.config, cname) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Foreach calls fn once for every name/value pair in the configuration,
+// stopping at the first error returned by fn.
+//
+// Real world configs commonly repeat keys (remote.*.fetch, http.<url>.*,
+// include.path); Foreach is how callers enumerate them, since GetString and
+// friends only ever return a single value.
+func (c *Config) Foreach(fn func(name, value string) error) error {
+	configCallbackMu.Lock()
+	defer configCallbackMu.Unlock()
+	configForeachFunc = fn
+	configForeachErr = nil
+	defer func() { configForeachFunc = nil }()
+	err := callC(func() C.int {
+		return C.git_config_foreach(c.config, C.git_config_foreach_cb(C.configForeachCallback), nil)
+	})
+	if err != nil {
+		if configForeachErr != nil {
+			return configForeachErr
+		}
+		return err
+	}
+	return nil
+}
+
+// GetMultivar returns every value of name whose value matches regexp, via
+// git_config_get_multivar. Pass an empty regexp to match every value.
+func (c *Config) GetMultivar(name, regexp string) ([]string, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var cregexp *C.char
+	if regexp != "" {
+		cregexp = C.CString(regexp)
+		defer C.free(unsafe.Pointer(cregexp))
+	}
+	var values []string
+	configCallbackMu.Lock()
+	defer configCallbackMu.Unlock()
+	configForeachFunc = func(_, value string) error {
+		values = append(values, value)
+		return nil
+	}
+	configForeachErr = nil
+	defer func() { configForeachFunc = nil }()
+	err := callC(func() C.int {
+		return C.git_config_get_multivar(c.config, cname, cregexp, C.git_config_foreach_cb(C.configForeachCallback), nil)
+	})
+	if err != nil {
+		if configForeachErr != nil {
+			return nil, configForeachErr
+		}
+		return nil, err
+	}
+	return values, nil
+}
+
+// SetMultivar sets the value of one or more entries named name whose current
+// value matches regexp, via git_config_set_multivar. If no existing value
+// matches regexp, a new entry is added.
+func (c *Config) SetMultivar(name, regexp, value string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cregexp := C.CString(regexp)
+	defer C.free(unsafe.Pointer(cregexp))
+	cvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(cvalue))
+	if err := callC(func() C.int { return C.git_config_set_multivar(c.config, cname, cregexp, cvalue) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddMultivar adds a new value to name without touching any existing values,
+// leaving repeated keys such as remote.origin.fetch alone.
+func (c *Config) AddMultivar(name, value string) error {
+	// "a^" can never match, so no existing value (not even an empty one) is
+	// replaced.
+	return c.SetMultivar(name, "a^", value)
+}
@@ -0,0 +1,301 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"path"
+	"testing"
+)
+
+func TestConfigGetBool(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignorecase, err := config.GetBool("core.ignorecase")
+	if err != nil {
+		t.Fatal(err)
+	} else if !ignorecase {
+		t.Error("Failed to get core.ignorecase. Want true, got false.")
+	}
+}
+
+func TestConfigGetString(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := config.GetString("github.user")
+	if err != nil {
+		t.Fatal(err)
+	} else if user != "fsouza" {
+		t.Errorf("Failed to get github.user. Want fsouza, got %s.", user)
+	}
+}
+
+func TestConfigGetInt64(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, err := config.GetInt64("section.commits")
+	if err != nil {
+		t.Fatal(err)
+	} else if commits != 800 {
+		t.Errorf("Failed to get section.commits. Want 800, got %d.", commits)
+	}
+}
+
+func TestConfigSetBool(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.SetBool("core.ignorecase", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.SetBool("github.login", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignorecase, _ := config.GetBool("core.ignorecase")
+	if ignorecase {
+		t.Error("Failed to set core.ignorecase to false.")
+	}
+	login, err := config.GetBool("github.login")
+	if err != nil {
+		t.Fatal(err)
+	} else if !login {
+		t.Error("Set github.login to false instead of setting it to true.")
+	}
+}
+
+func TestConfigSetInt64(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.SetInt64("section.commits", 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.SetInt64("section.errors", -10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, _ := config.GetInt64("section.commits")
+	if commits != 300 {
+		t.Errorf("Failed to get the right value for commits. Want 300, got %d.", commits)
+	}
+	errors, _ := config.GetInt64("section.errors")
+	if errors != -10 {
+		t.Errorf("Failed to errors. Want -10, got %d.", errors)
+	}
+}
+
+func TestConfigSetString(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.SetString("github.user", "franciscosouza")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, _ := config.GetString("github.user")
+	if user != "franciscosouza" {
+		t.Errorf("Failed to set github.user value, it's %s.", user)
+	}
+}
+
+func TestConfigOpenLevel(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := config.OpenLevel(ConfigLevelLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Free()
+	user, err := local.GetString("github.user")
+	if err != nil {
+		t.Fatal(err)
+	} else if user != "fsouza" {
+		t.Errorf("Failed to get github.user from the local level. Want fsouza, got %s.", user)
+	}
+}
+
+func TestConfigSetStringAt(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.SetStringAt("github.user", "franciscosouza", ConfigLevelLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, _ := config.GetString("github.user")
+	if user != "franciscosouza" {
+		t.Errorf("Failed to set github.user value, it's %s.", user)
+	}
+}
+
+func TestConfigForeach(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]string)
+	err = config.Foreach(func(name, value string) error {
+		seen[name] = value
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen["github.user"] != "fsouza" {
+		t.Errorf("Foreach did not visit github.user. Got %#v.", seen)
+	}
+}
+
+func TestConfigDelete(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.Delete("github.user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = config.GetString("github.user")
+	if err == nil {
+		t.Error("Expected an error after deleting github.user, got nil.")
+	}
+}
+
+func TestConfigMultivar(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.AddMultivar("remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.AddMultivar("remote.origin.fetch", "+refs/tags/*:refs/tags/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := config.GetMultivar("remote.origin.fetch", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 {
+		t.Errorf("Expected 2 values for remote.origin.fetch, got %d: %#v.", len(values), values)
+	}
+}
+
+func TestConfigAddFile(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.AddFile(path.Join(p, ".git", "config"), ConfigLevelApp, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := config.GetString("github.user")
+	if err != nil {
+		t.Fatal(err)
+	} else if user != "fsouza" {
+		t.Errorf("Failed to get github.user after adding app-level file. Want fsouza, got %s.", user)
+	}
+}
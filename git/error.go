@@ -0,0 +1,182 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// ErrorCode mirrors the int codes libgit2's C functions return directly
+// (GIT_ENOTFOUND, GIT_EEXISTS, and so on), letting callers branch on what
+// went wrong instead of string-matching a message.
+type ErrorCode int
+
+const (
+	ErrorCodeOK             ErrorCode = C.GIT_OK
+	ErrorCodeError          ErrorCode = C.GIT_ERROR
+	ErrorCodeNotFound       ErrorCode = C.GIT_ENOTFOUND
+	ErrorCodeExists         ErrorCode = C.GIT_EEXISTS
+	ErrorCodeAmbiguous      ErrorCode = C.GIT_EAMBIGUOUS
+	ErrorCodeBufs           ErrorCode = C.GIT_EBUFS
+	ErrorCodeUser           ErrorCode = C.GIT_EUSER
+	ErrorCodeBareRepo       ErrorCode = C.GIT_EBAREREPO
+	ErrorCodeUnbornBranch   ErrorCode = C.GIT_EUNBORNBRANCH
+	ErrorCodeUnmerged       ErrorCode = C.GIT_EUNMERGED
+	ErrorCodeNonFastForward ErrorCode = C.GIT_ENONFASTFORWARD
+	ErrorCodeInvalidSpec    ErrorCode = C.GIT_EINVALIDSPEC
+	ErrorCodeConflict       ErrorCode = C.GIT_ECONFLICT
+	ErrorCodeLocked         ErrorCode = C.GIT_ELOCKED
+	ErrorCodeModified       ErrorCode = C.GIT_EMODIFIED
+	ErrorCodeAuth           ErrorCode = C.GIT_EAUTH
+	ErrorCodeCertificate    ErrorCode = C.GIT_ECERTIFICATE
+	ErrorCodeApplied        ErrorCode = C.GIT_EAPPLIED
+	ErrorCodePeel           ErrorCode = C.GIT_EPEEL
+	ErrorCodeEOF            ErrorCode = C.GIT_EEOF
+	ErrorCodePassthrough    ErrorCode = C.GIT_PASSTHROUGH
+	ErrorCodeIterOver       ErrorCode = C.GIT_ITEROVER
+)
+
+// ErrorClass identifies the subsystem that produced an error (config,
+// reference, net, ...), mirroring git_error_t/GITERR_*.
+type ErrorClass int
+
+const (
+	ErrorClassNone       ErrorClass = C.GITERR_NONE
+	ErrorClassNoMemory   ErrorClass = C.GITERR_NOMEMORY
+	ErrorClassOS         ErrorClass = C.GITERR_OS
+	ErrorClassInvalid    ErrorClass = C.GITERR_INVALID
+	ErrorClassReference  ErrorClass = C.GITERR_REFERENCE
+	ErrorClassZlib       ErrorClass = C.GITERR_ZLIB
+	ErrorClassRepository ErrorClass = C.GITERR_REPOSITORY
+	ErrorClassConfig     ErrorClass = C.GITERR_CONFIG
+	ErrorClassRegex      ErrorClass = C.GITERR_REGEX
+	ErrorClassOdb        ErrorClass = C.GITERR_ODB
+	ErrorClassIndex      ErrorClass = C.GITERR_INDEX
+	ErrorClassObject     ErrorClass = C.GITERR_OBJECT
+	ErrorClassNet        ErrorClass = C.GITERR_NET
+	ErrorClassTag        ErrorClass = C.GITERR_TAG
+	ErrorClassTree       ErrorClass = C.GITERR_TREE
+	ErrorClassIndexer    ErrorClass = C.GITERR_INDEXER
+	ErrorClassSSL        ErrorClass = C.GITERR_SSL
+	ErrorClassSubmodule  ErrorClass = C.GITERR_SUBMODULE
+	ErrorClassThread     ErrorClass = C.GITERR_THREAD
+	ErrorClassStash      ErrorClass = C.GITERR_STASH
+	ErrorClassCheckout   ErrorClass = C.GITERR_CHECKOUT
+	ErrorClassFetchHead  ErrorClass = C.GITERR_FETCHHEAD
+	ErrorClassMerge      ErrorClass = C.GITERR_MERGE
+	ErrorClassSSH        ErrorClass = C.GITERR_SSH
+	ErrorClassFilter     ErrorClass = C.GITERR_FILTER
+)
+
+// GitError is the type used for errors in this package. Code is the int
+// libgit2 returned from the call that failed; Class and Message come from
+// the matching giterr_last entry.
+type GitError struct {
+	Code    ErrorCode
+	Class   ErrorClass
+	Message string
+}
+
+func (err GitError) Error() string {
+	return err.Message
+}
+
+// callC runs fn, a single cgo call, with the calling goroutine locked to its
+// current OS thread, and turns a non-GIT_OK return into a GitError read from
+// giterr_last.
+//
+// giterr_last is backed by thread-local storage, so the read has to happen
+// on the same OS thread as the call that set it. Locking only once fn has
+// already returned is too late: a cgo call crosses the runtime's
+// enter/exit-syscall boundary, so the goroutine is free to be rescheduled
+// onto a different OS thread the moment fn returns, and by the time
+// giterr_last runs it may read a different thread's error entirely. Holding
+// the lock across both the call and the read is what keeps them on the same
+// thread.
+func callC(fn func() C.int) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if ret := fn(); ret != C.GIT_OK {
+		return lastErr(ret)
+	}
+	return nil
+}
+
+// lastErr builds a GitError for a call that just returned ret, reading the
+// thread-local error libgit2 recorded for it via giterr_last. Callers must
+// already be locked to the OS thread the failing call ran on; callC
+// arranges that.
+func lastErr(ret C.int) GitError {
+	cerr := C.giterr_last()
+	if cerr == nil {
+		return GitError{Code: ErrorCode(ret), Message: fmt.Sprintf("unknown git error (code %d)", int(ret))}
+	}
+	return GitError{
+		Code:    ErrorCode(ret),
+		Class:   ErrorClass(cerr.klass),
+		Message: C.GoString(cerr.message),
+	}
+}
+
+func errCode(err error) (ErrorCode, bool) {
+	gitErr, ok := err.(GitError)
+	if !ok {
+		return 0, false
+	}
+	return gitErr.Code, true
+}
+
+// IsNotFound reports whether err is a GitError with code GIT_ENOTFOUND.
+func IsNotFound(err error) bool {
+	code, ok := errCode(err)
+	return ok && code == ErrorCodeNotFound
+}
+
+// IsExists reports whether err is a GitError with code GIT_EEXISTS.
+func IsExists(err error) bool {
+	code, ok := errCode(err)
+	return ok && code == ErrorCodeExists
+}
+
+// IsAmbiguous reports whether err is a GitError with code GIT_EAMBIGUOUS.
+func IsAmbiguous(err error) bool {
+	code, ok := errCode(err)
+	return ok && code == ErrorCodeAmbiguous
+}
+
+// IsConflict reports whether err is a GitError with code GIT_ECONFLICT.
+func IsConflict(err error) bool {
+	code, ok := errCode(err)
+	return ok && code == ErrorCodeConflict
+}
+
+// IsLocked reports whether err is a GitError with code GIT_ELOCKED.
+func IsLocked(err error) bool {
+	code, ok := errCode(err)
+	return ok && code == ErrorCodeLocked
+}
+
+// IsAuth reports whether err is a GitError with code GIT_EAUTH.
+func IsAuth(err error) bool {
+	code, ok := errCode(err)
+	return ok && code == ErrorCodeAuth
+}
+
+func init() {
+	C.git_libgit2_init()
+}
+
+// Shutdown releases the resources allocated by the package's call to
+// git_libgit2_init. libgit2 itself keeps an internal reference count across
+// init/shutdown pairs, so Shutdown is only meant to be called once, when a
+// process is completely done using this package.
+func Shutdown() {
+	C.git_libgit2_shutdown()
+}
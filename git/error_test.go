@@ -0,0 +1,63 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import "testing"
+
+func TestGitErrorIsAnError(t *testing.T) {
+	var _ error = GitError{}
+}
+
+func TestGitError(t *testing.T) {
+	expected := "Failed to do git stuff."
+	err := GitError{Code: ErrorCodeError, Message: expected}
+	got := err.Error()
+	if got != expected {
+		t.Errorf("GitError failed on checking message. Want %s, got %s.", expected, got)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	config, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = config.GetString("this.key.does.not.exist")
+	if err == nil {
+		t.Fatal("Expected an error looking up a missing key, got nil.")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("Expected IsNotFound to be true for %v.", err)
+	}
+}
+
+func TestIsExists(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	first, err := r.CreateRemote("origin", "https://example.com/repo.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Free()
+	_, err = r.CreateRemote("origin", "https://example.com/other.git")
+	if err == nil {
+		t.Fatal("Expected an error creating a remote that already exists, got nil.")
+	}
+	if !IsExists(err) {
+		t.Errorf("Expected IsExists to be true for %v.", err)
+	}
+}
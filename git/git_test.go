@@ -56,150 +56,6 @@ func removeRepository(path string) {
 	}
 }
 
-func TestConfigGetBool(t *testing.T) {
-	p := createRepository()
-	defer removeRepository(p)
-	r, err := OpenRepository(p)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer r.Free()
-	config, err := r.Config()
-	if err != nil {
-		t.Fatal(err)
-	}
-	ignorecase, err := config.GetBool("core.ignorecase")
-	if err != nil {
-		t.Fatal(err)
-	} else if !ignorecase {
-		t.Error("Failed to get core.ignorecase. Want true, got false.")
-	}
-}
-
-func TestConfigGetString(t *testing.T) {
-	p := createRepository()
-	defer removeRepository(p)
-	r, err := OpenRepository(p)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer r.Free()
-	config, err := r.Config()
-	if err != nil {
-		t.Fatal(err)
-	}
-	user, err := config.GetString("github.user")
-	if err != nil {
-		t.Fatal(err)
-	} else if user != "fsouza" {
-		t.Errorf("Failed to get github.user. Want fsouza, got %s.", user)
-	}
-}
-
-func TestConfigGetInt64(t *testing.T) {
-	p := createRepository()
-	defer removeRepository(p)
-	r, err := OpenRepository(p)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer r.Free()
-	config, err := r.Config()
-	if err != nil {
-		t.Fatal(err)
-	}
-	commits, err := config.GetInt64("section.commits")
-	if err != nil {
-		t.Fatal(err)
-	} else if commits != 800 {
-		t.Errorf("Failed to get section.commits. Want 800, got %d.", commits)
-	}
-}
-
-func TestConfigSetBool(t *testing.T) {
-	p := createRepository()
-	defer removeRepository(p)
-	r, err := OpenRepository(p)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer r.Free()
-	config, err := r.Config()
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = config.SetBool("core.ignorecase", false)
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = config.SetBool("github.login", true)
-	if err != nil {
-		t.Fatal(err)
-	}
-	ignorecase, _ := config.GetBool("core.ignorecase")
-	if ignorecase {
-		t.Error("Failed to set core.ignorecase to false.")
-	}
-	login, err := config.GetBool("github.login")
-	if err != nil {
-		t.Fatal(err)
-	} else if !login {
-		t.Error("Set github.login to false instead of setting it to true.")
-	}
-}
-
-func TestConfigSetInt64(t *testing.T) {
-	p := createRepository()
-	defer removeRepository(p)
-	r, err := OpenRepository(p)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer r.Free()
-	config, err := r.Config()
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = config.SetInt64("section.commits", 300)
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = config.SetInt64("section.errors", -10)
-	if err != nil {
-		t.Fatal(err)
-	}
-	commits, _ := config.GetInt64("section.commits")
-	if commits != 300 {
-		t.Errorf("Failed to get the right value for commits. Want 300, got %d.", commits)
-	}
-	errors, _ := config.GetInt64("section.errors")
-	if errors != -10 {
-		t.Errorf("Failed to errors. Want -10, got %d.", errors)
-	}
-}
-
-func TestConfigSetString(t *testing.T) {
-	p := createRepository()
-	defer removeRepository(p)
-	r, err := OpenRepository(p)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer r.Free()
-	config, err := r.Config()
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = config.SetString("github.user", "franciscosouza")
-	if err != nil {
-		t.Fatal(err)
-	}
-	user, _ := config.GetString("github.user")
-	if user != "franciscosouza" {
-		t.Errorf("Failed to set github.user value, it's %s.", user)
-	}
-}
-
 func TestHead(t *testing.T) {
 	p := createRepository()
 	defer removeRepository(p)
@@ -220,16 +76,3 @@ func TestHead(t *testing.T) {
 		t.Errorf("Failed to get head. Want %s, got %s.", last, commit.Id())
 	}
 }
-
-func TestGitErrorIsAnError(t *testing.T) {
-	var _ error = GitError("")
-}
-
-func TestGitError(t *testing.T) {
-	expected := "Failed to do git stuff."
-	err := GitError(expected)
-	got := err.Error()
-	if got != expected {
-		t.Errorf("GitError failed on checking message. Want %s, got %s.", expected, got)
-	}
-}
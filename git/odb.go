@@ -0,0 +1,219 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"io"
+	"runtime"
+	"unsafe"
+)
+
+// ObjectType identifies the kind of object stored in an Odb, mirroring
+// git_otype.
+type ObjectType int
+
+const (
+	ObjectAny    ObjectType = C.GIT_OBJ_ANY
+	ObjectBad    ObjectType = C.GIT_OBJ_BAD
+	ObjectCommit ObjectType = C.GIT_OBJ_COMMIT
+	ObjectTree   ObjectType = C.GIT_OBJ_TREE
+	ObjectBlob   ObjectType = C.GIT_OBJ_BLOB
+	ObjectTag    ObjectType = C.GIT_OBJ_TAG
+)
+
+// Odb represents a git object database, the content-addressable store
+// backing a Repository.
+type Odb struct {
+	odb *C.struct_git_odb
+}
+
+// Odb returns the object database backing the repository, via
+// git_repository_odb.
+func (r *Repository) Odb() (*Odb, error) {
+	odb := new(Odb)
+	if err := callC(func() C.int { return C.git_repository_odb(&odb.odb, r.repository) }); err != nil {
+		return nil, err
+	}
+	return odb, nil
+}
+
+// Free is used to deallocate the Odb instance.
+func (o *Odb) Free() {
+	C.git_odb_free(o.odb)
+}
+
+// OdbObject represents a single object read back from an Odb via Read.
+type OdbObject struct {
+	object *C.git_odb_object
+}
+
+// Free is used to deallocate the OdbObject instance.
+func (o *OdbObject) Free() {
+	C.git_odb_object_free(o.object)
+}
+
+// Id returns the hash of the object.
+func (o *OdbObject) Id() *Oid {
+	return newOidFromC(C.git_odb_object_id(o.object))
+}
+
+// Type returns the type of the object.
+func (o *OdbObject) Type() ObjectType {
+	return ObjectType(C.git_odb_object_type(o.object))
+}
+
+// Data returns a copy of the object's raw, uncompressed content.
+func (o *OdbObject) Data() []byte {
+	size := C.git_odb_object_size(o.object)
+	return C.GoBytes(C.git_odb_object_data(o.object), C.int(size))
+}
+
+// Read looks up an object by its id, via git_odb_read.
+func (o *Odb) Read(oid *Oid) (*OdbObject, error) {
+	object := new(OdbObject)
+	if err := callC(func() C.int { return C.git_odb_read(&object.object, o.odb, oid.toC()) }); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// Exists reports whether an object with the given id is present in the
+// database, via git_odb_exists.
+func (o *Odb) Exists(oid *Oid) bool {
+	return C.git_odb_exists(o.odb, oid.toC()) != 0
+}
+
+// Write stores data as a new object of type otype, via git_odb_write,
+// returning the id of the newly written object.
+func (o *Odb) Write(data []byte, otype ObjectType) (*Oid, error) {
+	var cdata unsafe.Pointer
+	if len(data) > 0 {
+		cdata = unsafe.Pointer(&data[0])
+	}
+	var coid C.git_oid
+	if err := callC(func() C.int {
+		return C.git_odb_write(&coid, o.odb, cdata, C.size_t(len(data)), C.git_otype(otype))
+	}); err != nil {
+		return nil, err
+	}
+	return newOidFromC(&coid), nil
+}
+
+// Hash computes the id that data would be stored under if it were written
+// as an object of type otype, without actually writing it, via
+// git_odb_hash.
+func Hash(data []byte, otype ObjectType) (*Oid, error) {
+	var cdata unsafe.Pointer
+	if len(data) > 0 {
+		cdata = unsafe.Pointer(&data[0])
+	}
+	var coid C.git_oid
+	if err := callC(func() C.int {
+		return C.git_odb_hash(&coid, cdata, C.size_t(len(data)), C.git_otype(otype))
+	}); err != nil {
+		return nil, err
+	}
+	return newOidFromC(&coid), nil
+}
+
+// OdbReadStream streams the content of a large object out of an Odb. It
+// implements io.ReadCloser.
+type OdbReadStream struct {
+	stream *C.git_odb_stream
+}
+
+// NewReadStream opens a streaming reader for the object identified by oid,
+// via git_odb_open_rstream.
+func (o *Odb) NewReadStream(oid *Oid) (*OdbReadStream, error) {
+	stream := new(OdbReadStream)
+	if err := callC(func() C.int { return C.git_odb_open_rstream(&stream.stream, o.odb, oid.toC()) }); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Read implements io.Reader.
+//
+// git_odb_stream_read's return doubles as both a byte count and an error
+// code, so it doesn't fit callC's GIT_OK-or-error shape; the lock is taken
+// by hand instead.
+func (s *OdbReadStream) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := C.size_t(len(p))
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	ret := C.git_odb_stream_read(s.stream, (*C.char)(unsafe.Pointer(&p[0])), n)
+	if ret < 0 {
+		return 0, lastErr(ret)
+	}
+	if ret == 0 {
+		return 0, io.EOF
+	}
+	return int(ret), nil
+}
+
+// Close implements io.Closer.
+func (s *OdbReadStream) Close() error {
+	C.git_odb_stream_free(s.stream)
+	return nil
+}
+
+// OdbWriteStream streams content into a new object in an Odb. It implements
+// io.WriteCloser; the object is only finalized once Close is called.
+type OdbWriteStream struct {
+	stream *C.git_odb_stream
+	id     *Oid
+}
+
+// NewWriteStream opens a streaming writer for a new object of size bytes and
+// type otype, via git_odb_open_wstream.
+func (o *Odb) NewWriteStream(size int64, otype ObjectType) (*OdbWriteStream, error) {
+	stream := new(OdbWriteStream)
+	if err := callC(func() C.int {
+		return C.git_odb_open_wstream(&stream.stream, o.odb, C.size_t(size), C.git_otype(otype))
+	}); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Write implements io.Writer.
+func (s *OdbWriteStream) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := callC(func() C.int {
+		return C.git_odb_stream_write(s.stream, (*C.char)(unsafe.Pointer(&p[0])), C.size_t(len(p)))
+	}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close finalizes the object and frees the stream, via
+// git_odb_stream_finalize_write. After a successful Close, Id returns the
+// hash of the written object.
+func (s *OdbWriteStream) Close() error {
+	defer C.git_odb_stream_free(s.stream)
+	var coid C.git_oid
+	if err := callC(func() C.int { return C.git_odb_stream_finalize_write(&coid, s.stream) }); err != nil {
+		return err
+	}
+	s.id = newOidFromC(&coid)
+	return nil
+}
+
+// Id returns the hash of the written object. It is only valid after a
+// successful call to Close.
+func (s *OdbWriteStream) Id() *Oid {
+	return s.id
+}
@@ -0,0 +1,138 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestOdbWriteAndRead(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	odb, err := r.Odb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer odb.Free()
+	data := []byte("blob content\n")
+	oid, err := odb.Write(data, ObjectBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !odb.Exists(oid) {
+		t.Error("Expected the written object to exist in the odb.")
+	}
+	object, err := odb.Read(oid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer object.Free()
+	if object.Type() != ObjectBlob {
+		t.Errorf("Want type %d, got %d.", ObjectBlob, object.Type())
+	}
+	if !bytes.Equal(object.Data(), data) {
+		t.Errorf("Want %q, got %q.", data, object.Data())
+	}
+}
+
+func TestHash(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	odb, err := r.Odb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer odb.Free()
+	data := []byte("blob content\n")
+	hashed, err := Hash(data, ObjectBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := odb.Write(data, ObjectBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hashed.Equal(written) {
+		t.Errorf("Want hash %s to match the id of the written object %s.", hashed, written)
+	}
+}
+
+func TestOdbWriteStream(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	odb, err := r.Odb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer odb.Free()
+	data := []byte("streamed blob content\n")
+	stream, err := odb.NewWriteStream(int64(len(data)), ObjectBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+	object, err := odb.Read(stream.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer object.Free()
+	if !bytes.Equal(object.Data(), data) {
+		t.Errorf("Want %q, got %q.", data, object.Data())
+	}
+}
+
+func TestOdbReadStream(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	odb, err := r.Odb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer odb.Free()
+	data := []byte("blob content for streaming read\n")
+	oid, err := odb.Write(data, ObjectBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := odb.NewReadStream(oid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	read, err := ioutil.ReadAll(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Errorf("Want %q, got %q.", data, read)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// oidSize is the length, in bytes, of a raw SHA-1 object id.
+const oidSize = C.GIT_OID_RAWSZ
+
+// Oid represents the unique identifier (a SHA-1 hash) of a git object.
+type Oid [oidSize]byte
+
+// NewOidFromString parses the hex representation of an object id, via
+// git_oid_fromstr.
+func NewOidFromString(s string) (*Oid, error) {
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	var coid C.git_oid
+	if err := callC(func() C.int { return C.git_oid_fromstr(&coid, cs) }); err != nil {
+		return nil, err
+	}
+	return newOidFromC(&coid), nil
+}
+
+func newOidFromC(coid *C.git_oid) *Oid {
+	oid := new(Oid)
+	copy(oid[:], C.GoBytes(unsafe.Pointer(&coid.id[0]), C.int(oidSize)))
+	return oid
+}
+
+func (oid *Oid) toC() *C.git_oid {
+	var coid C.git_oid
+	for i := 0; i < oidSize; i++ {
+		coid.id[i] = C.uint8_t(oid[i])
+	}
+	return &coid
+}
+
+// String returns the hex representation of the Oid.
+func (oid *Oid) String() string {
+	coid := oid.toC()
+	id := C.git_oid_allocfmt(coid)
+	defer C.free(unsafe.Pointer(id))
+	return C.GoString(id)
+}
+
+// Cmp compares two Oids byte by byte, returning a negative number if oid
+// sorts before other, 0 if they're equal and a positive number otherwise.
+func (oid *Oid) Cmp(other *Oid) int {
+	for i := 0; i < oidSize; i++ {
+		if oid[i] != other[i] {
+			return int(oid[i]) - int(other[i])
+		}
+	}
+	return 0
+}
+
+// Equal reports whether oid and other represent the same object id.
+func (oid *Oid) Equal(other *Oid) bool {
+	return oid.Cmp(other) == 0
+}
@@ -0,0 +1,60 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import "testing"
+
+func TestNewOidFromString(t *testing.T) {
+	s := "ce08fe4884650f067bd5703044d24d3c6b2c92b2"
+	oid, err := NewOidFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oid.String() != s {
+		t.Errorf("Want %s, got %s.", s, oid.String())
+	}
+}
+
+func TestNewOidFromStringInvalid(t *testing.T) {
+	_, err := NewOidFromString("not-a-sha")
+	if err == nil {
+		t.Error("Expected an error for an invalid oid string, got nil.")
+	}
+}
+
+func TestOidEqual(t *testing.T) {
+	s := "ce08fe4884650f067bd5703044d24d3c6b2c92b2"
+	oid1, err := NewOidFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oid2, err := NewOidFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !oid1.Equal(oid2) {
+		t.Error("Expected two oids parsed from the same string to be equal.")
+	}
+	if oid1.Cmp(oid2) != 0 {
+		t.Errorf("Expected Cmp to return 0 for equal oids, got %d.", oid1.Cmp(oid2))
+	}
+}
+
+func TestOidCmpDifferent(t *testing.T) {
+	oid1, err := NewOidFromString("ce08fe4884650f067bd5703044d24d3c6b2c92b2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oid2, err := NewOidFromString("0000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oid1.Equal(oid2) {
+		t.Error("Expected two oids parsed from different strings not to be equal.")
+	}
+	if oid1.Cmp(oid2) <= 0 {
+		t.Errorf("Expected Cmp to return a positive number, got %d.", oid1.Cmp(oid2))
+	}
+}
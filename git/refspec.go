@@ -0,0 +1,72 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// RefspecDirection identifies whether a Refspec is used for fetching or
+// pushing.
+type RefspecDirection int
+
+const (
+	RefspecDirectionFetch RefspecDirection = C.GIT_DIRECTION_FETCH
+	RefspecDirectionPush  RefspecDirection = C.GIT_DIRECTION_PUSH
+)
+
+// Refspec represents a parsed refspec, such as
+// "+refs/heads/*:refs/remotes/origin/*".
+type Refspec struct {
+	Src       string
+	Dst       string
+	Force     bool
+	Direction RefspecDirection
+
+	refspec *C.struct_git_refspec
+}
+
+// ParseRefspec parses a refspec string, via git_refspec_parse.
+func ParseRefspec(s string, direction RefspecDirection) (*Refspec, error) {
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	isFetch := C.int(0)
+	if direction == RefspecDirectionFetch {
+		isFetch = 1
+	}
+	refspec := new(Refspec)
+	if err := callC(func() C.int { return C.git_refspec_parse(&refspec.refspec, cs, isFetch) }); err != nil {
+		return nil, err
+	}
+	refspec.Src = C.GoString(C.git_refspec_src(refspec.refspec))
+	refspec.Dst = C.GoString(C.git_refspec_dst(refspec.refspec))
+	refspec.Force = C.git_refspec_force(refspec.refspec) != 0
+	refspec.Direction = direction
+	return refspec, nil
+}
+
+// Free is used to deallocate the Refspec instance.
+func (r *Refspec) Free() {
+	C.git_refspec_free(r.refspec)
+}
+
+// Transform maps a reference name through the refspec, via
+// git_refspec_transform, returning the resulting name (e.g. transforming
+// "refs/heads/master" through "refs/heads/*:refs/remotes/origin/*" yields
+// "refs/remotes/origin/master").
+func (r *Refspec) Transform(ref string) (string, error) {
+	cref := C.CString(ref)
+	defer C.free(unsafe.Pointer(cref))
+	var buf C.git_buf
+	defer C.git_buf_free(&buf)
+	if err := callC(func() C.int { return C.git_refspec_transform(&buf, r.refspec, cref) }); err != nil {
+		return "", err
+	}
+	return C.GoString(buf.ptr), nil
+}
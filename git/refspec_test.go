@@ -0,0 +1,57 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import "testing"
+
+func TestParseRefspec(t *testing.T) {
+	refspec, err := ParseRefspec("+refs/heads/*:refs/remotes/origin/*", RefspecDirectionFetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer refspec.Free()
+	if refspec.Src != "refs/heads/*" {
+		t.Errorf("Want src refs/heads/*, got %s.", refspec.Src)
+	}
+	if refspec.Dst != "refs/remotes/origin/*" {
+		t.Errorf("Want dst refs/remotes/origin/*, got %s.", refspec.Dst)
+	}
+	if !refspec.Force {
+		t.Error("Expected refspec to be a force update.")
+	}
+}
+
+func TestParseRefspecDirection(t *testing.T) {
+	// A push refspec's src may be any revision expression ("HEAD~1"), while
+	// a fetch refspec's src must be a valid reference name, which "HEAD~1"
+	// is not. This is what catches is_fetch/RefspecDirection polarity
+	// getting swapped, since the force-update sample above parses
+	// identically either way.
+	push, err := ParseRefspec("HEAD~1:refs/heads/topic", RefspecDirectionPush)
+	if err != nil {
+		t.Fatalf("Expected a revision-expression src to parse in push mode, got %s.", err)
+	}
+	defer push.Free()
+
+	_, err = ParseRefspec("HEAD~1:refs/heads/topic", RefspecDirectionFetch)
+	if err == nil {
+		t.Error("Expected a revision-expression src to be rejected in fetch mode.")
+	}
+}
+
+func TestRefspecTransform(t *testing.T) {
+	refspec, err := ParseRefspec("+refs/heads/*:refs/remotes/origin/*", RefspecDirectionFetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer refspec.Free()
+	transformed, err := refspec.Transform("refs/heads/master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transformed != "refs/remotes/origin/master" {
+		t.Errorf("Want refs/remotes/origin/master, got %s.", transformed)
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+//
+// extern int remoteCredentialsCallback(git_cred **cred, const char *url, const char *username_from_url, unsigned int allowed_types, void *payload);
+// extern int remoteSidebandProgressCallback(const char *str, int len, void *payload);
+// extern int remoteTransferProgressCallback(const git_transfer_progress *stats, void *payload);
+// extern int remoteCertificateCheckCallback(git_cert *cert, int valid, const char *host, void *payload);
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// CredType identifies the kind of credentials a transport is willing to
+// accept, as reported by a RemoteCallbacks.Credentials invocation.
+type CredType uint
+
+const (
+	CredTypeUserpassPlaintext CredType = C.GIT_CREDTYPE_USERPASS_PLAINTEXT
+	CredTypeSSHKey            CredType = C.GIT_CREDTYPE_SSH_KEY
+	CredTypeSSHCustom         CredType = C.GIT_CREDTYPE_SSH_CUSTOM
+	CredTypeDefault           CredType = C.GIT_CREDTYPE_DEFAULT
+)
+
+// Cred wraps a git_cred, one of the credential objects built by
+// NewCredUserpassPlaintext, NewCredSSHKey or NewCredSSHKeyFromAgent and
+// returned from a RemoteCallbacks.Credentials callback.
+type Cred struct {
+	cred *C.git_cred
+}
+
+// NewCredUserpassPlaintext builds a Cred from a plaintext username/password
+// pair, via git_cred_userpass_plaintext_new.
+func NewCredUserpassPlaintext(username, password string) (*Cred, error) {
+	cusername := C.CString(username)
+	defer C.free(unsafe.Pointer(cusername))
+	cpassword := C.CString(password)
+	defer C.free(unsafe.Pointer(cpassword))
+	cred := new(Cred)
+	if err := callC(func() C.int {
+		return C.git_cred_userpass_plaintext_new(&cred.cred, cusername, cpassword)
+	}); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// NewCredSSHKey builds a Cred from an SSH key pair on disk, via
+// git_cred_ssh_key_new.
+func NewCredSSHKey(username, publicKeyPath, privateKeyPath, passphrase string) (*Cred, error) {
+	cusername := C.CString(username)
+	defer C.free(unsafe.Pointer(cusername))
+	cpublicKeyPath := C.CString(publicKeyPath)
+	defer C.free(unsafe.Pointer(cpublicKeyPath))
+	cprivateKeyPath := C.CString(privateKeyPath)
+	defer C.free(unsafe.Pointer(cprivateKeyPath))
+	cpassphrase := C.CString(passphrase)
+	defer C.free(unsafe.Pointer(cpassphrase))
+	cred := new(Cred)
+	if err := callC(func() C.int {
+		return C.git_cred_ssh_key_new(&cred.cred, cusername, cpublicKeyPath, cprivateKeyPath, cpassphrase)
+	}); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// NewCredSSHKeyFromAgent builds a Cred that authenticates through a running
+// ssh-agent, via git_cred_ssh_key_from_agent.
+func NewCredSSHKeyFromAgent(username string) (*Cred, error) {
+	cusername := C.CString(username)
+	defer C.free(unsafe.Pointer(cusername))
+	cred := new(Cred)
+	if err := callC(func() C.int { return C.git_cred_ssh_key_from_agent(&cred.cred, cusername) }); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// TransferProgress reports the state of an in-progress fetch, mirroring
+// git_transfer_progress.
+type TransferProgress struct {
+	TotalObjects    uint
+	IndexedObjects  uint
+	ReceivedObjects uint
+	LocalObjects    uint
+	TotalDeltas     uint
+	IndexedDeltas   uint
+	ReceivedBytes   uint64
+}
+
+// RemoteCallbacks groups the callbacks a caller can plug into a Remote to
+// supply credentials, vet the peer certificate, or observe progress during a
+// Fetch or Push. Every field is optional; a nil field falls back to
+// libgit2's default behavior.
+type RemoteCallbacks struct {
+	Credentials      func(url, usernameFromURL string, allowedTypes CredType) (*Cred, error)
+	CertificateCheck func(valid bool, hostname string) error
+	SidebandProgress func(message string) error
+	TransferProgress func(stats TransferProgress) error
+}
+
+// callbackHandles bridges git_remote_callbacks' single void* payload back to
+// a *RemoteCallbacks. A Go pointer can't be handed to C as-is (the cgo
+// pointer-passing rules forbid giving C a Go pointer that itself points to
+// Go memory), so instead each registered *RemoteCallbacks gets a one-byte
+// C allocation whose address serves as an opaque, genuinely C-owned handle;
+// the map is keyed off that address rather than a uintptr conjured out of
+// thin air.
+var (
+	callbackHandlesMu sync.Mutex
+	callbackHandles   = map[unsafe.Pointer]*RemoteCallbacks{}
+)
+
+func registerCallbacks(cb *RemoteCallbacks) unsafe.Pointer {
+	handle := C.malloc(1)
+	callbackHandlesMu.Lock()
+	defer callbackHandlesMu.Unlock()
+	callbackHandles[handle] = cb
+	return handle
+}
+
+func lookupCallbacks(handle unsafe.Pointer) *RemoteCallbacks {
+	callbackHandlesMu.Lock()
+	defer callbackHandlesMu.Unlock()
+	return callbackHandles[handle]
+}
+
+func unregisterCallbacks(handle unsafe.Pointer) {
+	callbackHandlesMu.Lock()
+	delete(callbackHandles, handle)
+	callbackHandlesMu.Unlock()
+	C.free(handle)
+}
+
+//export remoteCredentialsCallback
+func remoteCredentialsCallback(cred **C.git_cred, url, usernameFromURL *C.char, allowedTypes C.uint, payload unsafe.Pointer) C.int {
+	cb := lookupCallbacks(payload)
+	if cb == nil || cb.Credentials == nil {
+		return C.GIT_PASSTHROUGH
+	}
+	c, err := cb.Credentials(C.GoString(url), C.GoString(usernameFromURL), CredType(allowedTypes))
+	if err != nil {
+		return -1
+	}
+	*cred = c.cred
+	return C.GIT_OK
+}
+
+//export remoteSidebandProgressCallback
+func remoteSidebandProgressCallback(str *C.char, length C.int, payload unsafe.Pointer) C.int {
+	cb := lookupCallbacks(payload)
+	if cb == nil || cb.SidebandProgress == nil {
+		return C.GIT_OK
+	}
+	message := C.GoStringN(str, length)
+	if err := cb.SidebandProgress(message); err != nil {
+		return -1
+	}
+	return C.GIT_OK
+}
+
+//export remoteTransferProgressCallback
+func remoteTransferProgressCallback(stats *C.git_transfer_progress, payload unsafe.Pointer) C.int {
+	cb := lookupCallbacks(payload)
+	if cb == nil || cb.TransferProgress == nil {
+		return C.GIT_OK
+	}
+	progress := TransferProgress{
+		TotalObjects:    uint(stats.total_objects),
+		IndexedObjects:  uint(stats.indexed_objects),
+		ReceivedObjects: uint(stats.received_objects),
+		LocalObjects:    uint(stats.local_objects),
+		TotalDeltas:     uint(stats.total_deltas),
+		IndexedDeltas:   uint(stats.indexed_deltas),
+		ReceivedBytes:   uint64(stats.received_bytes),
+	}
+	if err := cb.TransferProgress(progress); err != nil {
+		return -1
+	}
+	return C.GIT_OK
+}
+
+//export remoteCertificateCheckCallback
+func remoteCertificateCheckCallback(cert *C.git_cert, valid C.int, host *C.char, payload unsafe.Pointer) C.int {
+	cb := lookupCallbacks(payload)
+	if cb == nil || cb.CertificateCheck == nil {
+		return C.GIT_OK
+	}
+	if err := cb.CertificateCheck(valid != 0, C.GoString(host)); err != nil {
+		return -1
+	}
+	return C.GIT_OK
+}
+
+// FetchOptions controls the behavior of Remote.Fetch.
+type FetchOptions struct {
+	Callbacks *RemoteCallbacks
+}
+
+// PushOptions controls the behavior of Remote.Push.
+type PushOptions struct {
+	Callbacks *RemoteCallbacks
+}
+
+// Remote represents a remote repository, identified by a name and a URL.
+type Remote struct {
+	remote *C.struct_git_remote
+	handle unsafe.Pointer
+}
+
+// Free is used to deallocate the Remote instance.
+func (r *Remote) Free() {
+	if r.handle != nil {
+		unregisterCallbacks(r.handle)
+	}
+	C.git_remote_free(r.remote)
+}
+
+// CreateRemote adds a new remote with the given name and URL to the
+// repository, via git_remote_create.
+func (r *Repository) CreateRemote(name, url string) (*Remote, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	curl := C.CString(url)
+	defer C.free(unsafe.Pointer(curl))
+	remote := new(Remote)
+	if err := callC(func() C.int { return C.git_remote_create(&remote.remote, r.repository, cname, curl) }); err != nil {
+		return nil, err
+	}
+	return remote, nil
+}
+
+// LookupRemote loads a remote previously configured under the given name,
+// via git_remote_load.
+func (r *Repository) LookupRemote(name string) (*Remote, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	remote := new(Remote)
+	if err := callC(func() C.int { return C.git_remote_load(&remote.remote, r.repository, cname) }); err != nil {
+		return nil, err
+	}
+	return remote, nil
+}
+
+// applyCallbacks wires cb into the underlying git_remote, replacing whatever
+// was previously registered. A nil cb clears the remote's callbacks instead
+// of leaving a stale registration from an earlier call in place.
+func (r *Remote) applyCallbacks(cb *RemoteCallbacks) error {
+	if r.handle != nil {
+		unregisterCallbacks(r.handle)
+		r.handle = nil
+	}
+	var ccb C.git_remote_callbacks
+	ccb.version = C.GIT_REMOTE_CALLBACKS_VERSION
+	if cb != nil {
+		r.handle = registerCallbacks(cb)
+		ccb.credentials = C.git_cred_acquire_cb(C.remoteCredentialsCallback)
+		ccb.progress = C.git_transport_message_cb(C.remoteSidebandProgressCallback)
+		ccb.transfer_progress = C.git_transfer_progress_cb(C.remoteTransferProgressCallback)
+		if cb.CertificateCheck != nil {
+			ccb.certificate_check = C.git_transport_certificate_check_cb(C.remoteCertificateCheckCallback)
+		}
+		ccb.payload = r.handle
+	}
+	if err := callC(func() C.int { return C.git_remote_set_callbacks(r.remote, &ccb) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Fetch downloads and updates the given refspecs from the remote, via
+// git_remote_connect, git_remote_download, and git_remote_update_tips.
+//
+// An empty refspecs uses the remote's configured fetch refspec.
+func (r *Remote) Fetch(refspecs []string, opts *FetchOptions) error {
+	var callbacks *RemoteCallbacks
+	if opts != nil {
+		callbacks = opts.Callbacks
+	}
+	if err := r.applyCallbacks(callbacks); err != nil {
+		return err
+	}
+	if err := callC(func() C.int { return C.git_remote_connect(r.remote, C.GIT_DIRECTION_FETCH) }); err != nil {
+		return err
+	}
+	defer C.git_remote_disconnect(r.remote)
+	var cstrs []*C.char
+	for _, refspec := range refspecs {
+		cstrs = append(cstrs, C.CString(refspec))
+	}
+	defer func() {
+		for _, cstr := range cstrs {
+			C.free(unsafe.Pointer(cstr))
+		}
+	}()
+	var refspecArray C.git_strarray
+	if len(cstrs) > 0 {
+		refspecArray.strings = &cstrs[0]
+		refspecArray.count = C.size_t(len(cstrs))
+	}
+	if err := callC(func() C.int { return C.git_remote_download(r.remote, &refspecArray, nil) }); err != nil {
+		return err
+	}
+	if err := callC(func() C.int { return C.git_remote_update_tips(r.remote, nil, nil) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Push uploads the given refspecs to the remote, via the git_push API.
+func (r *Remote) Push(refspecs []string, opts *PushOptions) error {
+	var callbacks *RemoteCallbacks
+	if opts != nil {
+		callbacks = opts.Callbacks
+	}
+	if err := r.applyCallbacks(callbacks); err != nil {
+		return err
+	}
+	if err := callC(func() C.int { return C.git_remote_connect(r.remote, C.GIT_DIRECTION_PUSH) }); err != nil {
+		return err
+	}
+	defer C.git_remote_disconnect(r.remote)
+	var push *C.git_push
+	if err := callC(func() C.int { return C.git_push_new(&push, r.remote) }); err != nil {
+		return err
+	}
+	defer C.git_push_free(push)
+	for _, refspec := range refspecs {
+		crefspec := C.CString(refspec)
+		err := callC(func() C.int { return C.git_push_add_refspec(push, crefspec) })
+		C.free(unsafe.Pointer(crefspec))
+		if err != nil {
+			return err
+		}
+	}
+	if err := callC(func() C.int { return C.git_push_finish(push) }); err != nil {
+		return err
+	}
+	if C.git_push_unpack_ok(push) == 0 {
+		return GitError{Code: ErrorCodeError, Class: ErrorClassNet, Message: "the remote rejected the pushed packfile"}
+	}
+	return nil
+}
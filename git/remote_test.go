@@ -0,0 +1,173 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+)
+
+func TestCreateRemote(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	remote, err := r.CreateRemote("origin", "https://example.com/repo.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Free()
+}
+
+func TestLookupRemote(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	created, err := r.CreateRemote("origin", "https://example.com/repo.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.Free()
+	remote, err := r.LookupRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Free()
+}
+
+func TestRemoteFetch(t *testing.T) {
+	upstream := createRepository()
+	defer removeRepository(upstream)
+	tmpdir := os.TempDir()
+	clonePath := path.Join(tmpdir, "gitrepo-clone")
+	os.RemoveAll(clonePath)
+	defer os.RemoveAll(clonePath)
+	out, err := exec.Command("git", "clone", "-q", upstream, clonePath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to clone upstream (%s): %s", err, out)
+	}
+	r, err := OpenRepository(clonePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	remote, err := r.LookupRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Free()
+	err = remote.Fetch(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoteFetchWithCallbacks(t *testing.T) {
+	upstream := createRepository()
+	defer removeRepository(upstream)
+	tmpdir := os.TempDir()
+	clonePath := path.Join(tmpdir, "gitrepo-clone-callbacks")
+	os.RemoveAll(clonePath)
+	defer os.RemoveAll(clonePath)
+	out, err := exec.Command("git", "clone", "-q", upstream, clonePath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to clone upstream (%s): %s", err, out)
+	}
+	// Give the upstream something new so the fetch below actually transfers
+	// an object and the progress callback has something to report.
+	out, err = exec.Command("git", "--work-tree="+upstream, "--git-dir="+upstream+"/.git", "commit", "--allow-empty", "-m", "second commit").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to add a commit upstream (%s): %s", err, out)
+	}
+	r, err := OpenRepository(clonePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	remote, err := r.LookupRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Free()
+	var transferCalled bool
+	opts := &FetchOptions{
+		Callbacks: &RemoteCallbacks{
+			TransferProgress: func(stats TransferProgress) error {
+				transferCalled = true
+				return nil
+			},
+			// SidebandProgress only fires when the transport relays a
+			// server-side progress message; the local filesystem transport
+			// used here never sends one, so it's wired in for coverage but
+			// not asserted on.
+			SidebandProgress: func(message string) error {
+				return nil
+			},
+		},
+	}
+	if err := remote.Fetch(nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !transferCalled {
+		t.Error("expected TransferProgress callback to fire during Fetch")
+	}
+}
+
+func TestRemotePush(t *testing.T) {
+	upstream := createRepository()
+	defer removeRepository(upstream)
+	tmpdir := os.TempDir()
+	barePath := path.Join(tmpdir, "gitrepo-bare")
+	os.RemoveAll(barePath)
+	defer os.RemoveAll(barePath)
+	out, err := exec.Command("git", "clone", "-q", "--bare", upstream, barePath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to create bare upstream (%s): %s", err, out)
+	}
+	clonePath := path.Join(tmpdir, "gitrepo-push-clone")
+	os.RemoveAll(clonePath)
+	defer os.RemoveAll(clonePath)
+	out, err = exec.Command("git", "clone", "-q", barePath, clonePath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to clone bare upstream (%s): %s", err, out)
+	}
+	out, err = exec.Command("git", "--work-tree="+clonePath, "--git-dir="+clonePath+"/.git", "commit", "--allow-empty", "-m", "pushed commit").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to create a commit to push (%s): %s", err, out)
+	}
+	r, err := OpenRepository(clonePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	remote, err := r.LookupRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Free()
+	if err := remote.Push([]string{"refs/heads/master:refs/heads/master"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	want, err := exec.Command("git", "--work-tree="+clonePath, "--git-dir="+clonePath+"/.git", "log", "-1", "--format=format:%H").CombinedOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := exec.Command("git", "--git-dir="+barePath, "log", "-1", "--format=format:%H", "master").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to read pushed commit (%s): %s", err, got)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Push did not update upstream. Want %s, got %s.", want, got)
+	}
+}
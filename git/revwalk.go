@@ -0,0 +1,138 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+import "C"
+
+import (
+	"io"
+	"unsafe"
+)
+
+// SortMode controls the order in which RevWalk.Next visits commits. Modes
+// can be combined with a bitwise or, mirroring GIT_SORT_*.
+type SortMode int
+
+const (
+	SortNone        SortMode = C.GIT_SORT_NONE
+	SortTopological SortMode = C.GIT_SORT_TOPOLOGICAL
+	SortTime        SortMode = C.GIT_SORT_TIME
+	SortReverse     SortMode = C.GIT_SORT_REVERSE
+)
+
+// RevWalk traverses the commit history of a repository, in the style of
+// `git log`.
+type RevWalk struct {
+	walk       *C.git_revwalk
+	repository *C.struct_git_repository
+}
+
+// Walk creates a new RevWalk over the repository's history, via
+// git_revwalk_new.
+func (r *Repository) Walk() (*RevWalk, error) {
+	walk := &RevWalk{repository: r.repository}
+	if err := callC(func() C.int { return C.git_revwalk_new(&walk.walk, r.repository) }); err != nil {
+		return nil, err
+	}
+	return walk, nil
+}
+
+// Free is used to deallocate the RevWalk instance.
+func (w *RevWalk) Free() {
+	C.git_revwalk_free(w.walk)
+}
+
+// Push marks the commit identified by oid, and all of its ancestors, to be
+// visited by the walk, via git_revwalk_push.
+func (w *RevWalk) Push(oid *Oid) error {
+	if err := callC(func() C.int { return C.git_revwalk_push(w.walk, oid.toC()) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PushHead marks the commit pointed to by HEAD to be visited by the walk,
+// via git_revwalk_push_head.
+func (w *RevWalk) PushHead() error {
+	if err := callC(func() C.int { return C.git_revwalk_push_head(w.walk) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PushRef marks the commit pointed to by the given reference to be visited
+// by the walk, via git_revwalk_push_ref.
+func (w *RevWalk) PushRef(name string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_revwalk_push_ref(w.walk, cname) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Hide marks the commit identified by oid, and all of its ancestors, as
+// already visited, excluding them (and everything reachable only through
+// them) from the walk, via git_revwalk_hide.
+func (w *RevWalk) Hide(oid *Oid) error {
+	if err := callC(func() C.int { return C.git_revwalk_hide(w.walk, oid.toC()) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Sorting sets the order commits are returned in, via git_revwalk_sorting.
+func (w *RevWalk) Sorting(mode SortMode) {
+	C.git_revwalk_sorting(w.walk, C.uint(mode))
+}
+
+// SimplifyFirstParent makes the walk only follow the first parent of each
+// commit, via git_revwalk_simplify_first_parent.
+func (w *RevWalk) SimplifyFirstParent() {
+	C.git_revwalk_simplify_first_parent(w.walk)
+}
+
+// Next advances the walk and stores the id of the next commit in oid, via
+// git_revwalk_next. It returns io.EOF once every pushed commit has been
+// visited.
+func (w *RevWalk) Next(oid *Oid) error {
+	var coid C.git_oid
+	if err := callC(func() C.int { return C.git_revwalk_next(&coid, w.walk) }); err != nil {
+		if code, ok := errCode(err); ok && code == ErrorCodeIterOver {
+			return io.EOF
+		}
+		return err
+	}
+	copy(oid[:], (*newOidFromC(&coid))[:])
+	return nil
+}
+
+// Iterate calls fn once for every commit visited by the walk, in order,
+// stopping early if fn returns false. The Commit passed to fn is freed as
+// soon as fn returns, so callers that need to keep one around must look it
+// up again by id.
+func (w *RevWalk) Iterate(fn func(commit *Commit) bool) error {
+	oid := new(Oid)
+	for {
+		err := w.Next(oid)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		commit := new(Commit)
+		if err := callC(func() C.int { return C.git_commit_lookup(&commit.commit, w.repository, oid.toC()) }); err != nil {
+			return err
+		}
+		cont := fn(commit)
+		commit.Free()
+		if !cont {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import "testing"
+
+func TestRevWalkIterate(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	head, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer head.Free()
+	walk, err := r.Walk()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer walk.Free()
+	if err := walk.PushHead(); err != nil {
+		t.Fatal(err)
+	}
+	walk.Sorting(SortTime)
+	var ids []string
+	err = walk.Iterate(func(commit *Commit) bool {
+		ids = append(ids, commit.Id())
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != head.Id() {
+		t.Errorf("Want [%s], got %v.", head.Id(), ids)
+	}
+}
+
+func TestRevWalkPushAndNext(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	head, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer head.Free()
+	headOid, err := NewOidFromString(head.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk, err := r.Walk()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer walk.Free()
+	if err := walk.Push(headOid); err != nil {
+		t.Fatal(err)
+	}
+	var oid Oid
+	if err := walk.Next(&oid); err != nil {
+		t.Fatal(err)
+	}
+	if oid.String() != head.Id() {
+		t.Errorf("Want %s, got %s.", head.Id(), oid.String())
+	}
+	err = walk.Next(&oid)
+	if err == nil {
+		t.Error("Expected io.EOF after exhausting the walk, got nil.")
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+// #cgo pkg-config: libgit2
+// #include <git2.h>
+//
+// extern int treeWalkCallback(const char *root, const git_tree_entry *entry, void *payload);
+import "C"
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// Tree represents a git tree.
+type Tree struct {
+	tree *C.struct_git_tree
+}
+
+// Free is used to deallocate a git tree.
+func (t *Tree) Free() {
+	C.git_tree_free(t.tree)
+}
+
+// Id returns the hash of the tree.
+func (t *Tree) Id() string {
+	return newOidFromC(C.git_tree_id(t.tree)).String()
+}
+
+// EntryCount returns the number of entries in the tree.
+func (t *Tree) EntryCount() int {
+	return int(C.git_tree_entrycount(t.tree))
+}
+
+// TreeEntry represents a single entry in a Tree: a name, the id of the
+// object it points to, the type of that object and its file mode.
+//
+// TreeEntry does not have a Free method. git_tree_entry_byname,
+// git_tree_entry_byindex and git_tree_entry_bypath all hand back pointers
+// that are either borrowed from the tree or need an explicit free depending
+// on which one was called, a well known footgun in libgit2 bindings; to
+// sidestep it entirely, every TreeEntry wraps its own duplicate
+// (git_tree_entry_dup) and frees it via a finalizer.
+type TreeEntry struct {
+	entry *C.git_tree_entry
+}
+
+func newTreeEntry(centry *C.git_tree_entry) *TreeEntry {
+	var dup *C.git_tree_entry
+	C.git_tree_entry_dup(&dup, centry)
+	entry := &TreeEntry{entry: dup}
+	runtime.SetFinalizer(entry, func(e *TreeEntry) {
+		C.git_tree_entry_free(e.entry)
+	})
+	return entry
+}
+
+// Name returns the filename of the entry.
+func (e *TreeEntry) Name() string {
+	return C.GoString(C.git_tree_entry_name(e.entry))
+}
+
+// Id returns the id of the object the entry points to.
+func (e *TreeEntry) Id() *Oid {
+	return newOidFromC(C.git_tree_entry_id(e.entry))
+}
+
+// Type returns the type of the object the entry points to.
+func (e *TreeEntry) Type() ObjectType {
+	return ObjectType(C.git_tree_entry_type(e.entry))
+}
+
+// Filemode returns the Unix file mode of the entry.
+func (e *TreeEntry) Filemode() int {
+	return int(C.git_tree_entry_filemode(e.entry))
+}
+
+// EntryByName looks up an entry by its filename, via git_tree_entry_byname.
+// It returns nil if no such entry exists.
+func (t *Tree) EntryByName(name string) *TreeEntry {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	centry := C.git_tree_entry_byname(t.tree, cname)
+	if centry == nil {
+		return nil
+	}
+	return newTreeEntry(centry)
+}
+
+// EntryByIndex looks up an entry by its position in the tree, via
+// git_tree_entry_byindex. It returns nil if i is out of range.
+func (t *Tree) EntryByIndex(i int) *TreeEntry {
+	centry := C.git_tree_entry_byindex(t.tree, C.size_t(i))
+	if centry == nil {
+		return nil
+	}
+	return newTreeEntry(centry)
+}
+
+// EntryByPath looks up an entry by a path relative to the tree, descending
+// into subtrees as needed, via git_tree_entry_bypath.
+func (t *Tree) EntryByPath(path string) (*TreeEntry, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var centry *C.git_tree_entry
+	if err := callC(func() C.int { return C.git_tree_entry_bypath(&centry, t.tree, cpath) }); err != nil {
+		return nil, err
+	}
+	defer C.git_tree_entry_free(centry)
+	return newTreeEntry(centry), nil
+}
+
+// treeWalkFn holds the Go callback for the duration of a single Walk
+// call. git_tree_walk is synchronous, so a mutex-guarded package variable is
+// enough to bridge its C callback back into Go.
+var (
+	treeWalkMu sync.Mutex
+	treeWalkFn func(root string, entry *TreeEntry) int
+)
+
+//export treeWalkCallback
+func treeWalkCallback(root *C.char, centry *C.git_tree_entry, payload unsafe.Pointer) C.int {
+	return C.int(treeWalkFn(C.GoString(root), newTreeEntry(centry)))
+}
+
+// TreeWalkSkip is a return value for the callback passed to Walk: return it
+// (on a tree entry) to skip that subtree without stopping the walk, or
+// return a negative number to stop the walk altogether.
+const (
+	TreeWalkSkip = 1
+)
+
+// Walk visits every entry in the tree, recursing into subtrees, via
+// git_tree_walk with GIT_TREEWALK_PRE. fn is called with the root path of
+// the entry's parent (relative to the tree) and the entry itself.
+func (t *Tree) Walk(fn func(root string, entry *TreeEntry) int) error {
+	treeWalkMu.Lock()
+	defer treeWalkMu.Unlock()
+	treeWalkFn = fn
+	defer func() { treeWalkFn = nil }()
+	if err := callC(func() C.int {
+		return C.git_tree_walk(t.tree, C.GIT_TREEWALK_PRE, C.git_treewalk_cb(C.treeWalkCallback), nil)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TreeBuilder is used to create or modify trees in memory before writing
+// them to the object database.
+type TreeBuilder struct {
+	builder    *C.git_treebuilder
+	repository *C.struct_git_repository
+}
+
+// TreeBuilder creates a new, empty TreeBuilder, via git_treebuilder_create.
+func (r *Repository) TreeBuilder() (*TreeBuilder, error) {
+	builder := &TreeBuilder{repository: r.repository}
+	if err := callC(func() C.int { return C.git_treebuilder_create(&builder.builder, nil) }); err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
+// Free is used to deallocate the TreeBuilder instance.
+func (b *TreeBuilder) Free() {
+	C.git_treebuilder_free(b.builder)
+}
+
+// Insert adds or replaces an entry in the tree being built, via
+// git_treebuilder_insert.
+func (b *TreeBuilder) Insert(name string, oid *Oid, mode int) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int {
+		return C.git_treebuilder_insert(nil, b.builder, cname, oid.toC(), C.git_filemode_t(mode))
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remove removes an entry from the tree being built, via
+// git_treebuilder_remove.
+func (b *TreeBuilder) Remove(name string) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if err := callC(func() C.int { return C.git_treebuilder_remove(b.builder, cname) }); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Write persists the tree being built to the repository's object database,
+// via git_treebuilder_write, returning the id of the new tree.
+func (b *TreeBuilder) Write() (*Oid, error) {
+	var coid C.git_oid
+	if err := callC(func() C.int { return C.git_treebuilder_write(&coid, b.repository, b.builder) }); err != nil {
+		return nil, err
+	}
+	return newOidFromC(&coid), nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2012 Francisco Souza. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import "testing"
+
+func TestTreeEntryByName(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	commit, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer commit.Free()
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	if tree.EntryCount() != 1 {
+		t.Fatalf("Want 1 entry, got %d.", tree.EntryCount())
+	}
+	entry := tree.EntryByName("README")
+	if entry == nil {
+		t.Fatal("Expected to find an entry named README.")
+	}
+	if entry.Name() != "README" {
+		t.Errorf("Want README, got %s.", entry.Name())
+	}
+	if entry.Type() != ObjectBlob {
+		t.Errorf("Want type %d, got %d.", ObjectBlob, entry.Type())
+	}
+}
+
+func TestTreeEntryByNameMissing(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	commit, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer commit.Free()
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	if entry := tree.EntryByName("does-not-exist"); entry != nil {
+		t.Errorf("Expected no entry to be found, got %s.", entry.Name())
+	}
+}
+
+func TestTreeEntryByIndex(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	commit, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer commit.Free()
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	entry := tree.EntryByIndex(0)
+	if entry == nil {
+		t.Fatal("Expected to find an entry at index 0.")
+	}
+	if entry.Name() != "README" {
+		t.Errorf("Want README, got %s.", entry.Name())
+	}
+}
+
+func TestTreeEntryByPath(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	commit, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer commit.Free()
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	entry, err := tree.EntryByPath("README")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Name() != "README" {
+		t.Errorf("Want README, got %s.", entry.Name())
+	}
+}
+
+func TestTreeWalk(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	commit, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer commit.Free()
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	var names []string
+	err = tree.Walk(func(root string, entry *TreeEntry) int {
+		names = append(names, root+entry.Name())
+		return 0
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "README" {
+		t.Errorf("Want [README], got %v.", names)
+	}
+}
+
+func TestTreeBuilder(t *testing.T) {
+	p := createRepository()
+	defer removeRepository(p)
+	r, err := OpenRepository(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Free()
+	commit, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer commit.Free()
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Free()
+	readme := tree.EntryByName("README")
+	if readme == nil {
+		t.Fatal("Expected to find an entry named README.")
+	}
+	builder, err := r.TreeBuilder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer builder.Free()
+	err = builder.Insert("README", readme.Id(), readme.Filemode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = builder.Remove("README")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oid, err := builder.Write()
+	if err != nil {
+		t.Fatal(err)
+	}
+	odb, err := r.Odb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer odb.Free()
+	if !odb.Exists(oid) {
+		t.Error("Expected the tree built by TreeBuilder to be written to the odb.")
+	}
+}